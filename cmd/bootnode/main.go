@@ -0,0 +1,147 @@
+// Command bootnode runs a minimal, chain-agnostic libp2p node whose only
+// purpose is to help other nodes find each other: it runs a Kademlia DHT
+// and peer-exchange, but joins no gossipsub topics and handles no
+// chain-specific messages. It's the optimint equivalent of Ethereum's
+// `bootnode` and is meant to be listed in other nodes' P2PConfig.Seeds.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+
+	cmted25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cmtp2p "github.com/cometbft/cometbft/p2p"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/node"
+	"github.com/lazyledger/optimint/p2p"
+)
+
+func main() {
+	var (
+		addr        = flag.String("addr", p2p.DefaultListenAddress, "listen multiaddr")
+		nodeKeyPath = flag.String("nodekey", "", "path to a node key file in Tendermint NodeKey JSON format (raw key files are not accepted)")
+		genKey      = flag.Bool("genkey", false, "generate a new node key at --nodekey and exit")
+		rendezvous  = flag.String("rendezvous", "optimint-bootnode", "DHT rendezvous string to advertise and search under")
+		nat         = flag.Bool("nat", true, "enable NAT port mapping (UPnP/NAT-PMP)")
+		metrics     = flag.String("metrics", "", "if set, address (e.g. :8889) to serve Prometheus metrics on")
+	)
+	flag.Parse()
+
+	if *genKey {
+		if *nodeKeyPath == "" {
+			fmt.Fprintln(os.Stderr, "--genkey requires --nodekey to specify where to write the key")
+			os.Exit(1)
+		}
+		// LoadOrGenNodeKey silently returns the existing key if nodeKeyPath
+		// is already populated, which would make --genkey a no-op instead
+		// of rotating the key as requested. Refuse instead of guessing.
+		if _, err := os.Stat(*nodeKeyPath); err == nil {
+			fmt.Fprintf(os.Stderr, "--genkey: refusing to overwrite existing node key at %s\n", *nodeKeyPath)
+			os.Exit(1)
+		} else if !os.IsNotExist(err) {
+			fmt.Fprintln(os.Stderr, "failed to check --nodekey path:", err)
+			os.Exit(1)
+		}
+		nodeKey := &cmtp2p.NodeKey{PrivKey: cmted25519.GenPrivKey()}
+		if err := nodeKey.SaveAs(*nodeKeyPath); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to generate node key:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	privKey, err := loadOrGenerateKey(*nodeKeyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load node key:", err)
+		os.Exit(1)
+	}
+
+	logger := &stdLogger{}
+
+	conf := config.P2PConfig{ListenAddress: *addr, EnableNAT: *nat}
+
+	h, err := p2p.NewHost(conf, privKey)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to create host:", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kadDHT, err := p2p.NewDHT(ctx, h, nil, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to create DHT:", err)
+		os.Exit(1)
+	}
+
+	go p2p.Discover(ctx, h, kadDHT, *rendezvous, logger)
+
+	for _, a := range h.Addrs() {
+		fmt.Printf("%s/p2p/%s\n", a, h.ID())
+	}
+
+	if *metrics != "" {
+		registerMetrics(h, kadDHT)
+		go func() {
+			http.Handle("/metrics", promhttp.Handler())
+			if err := http.ListenAndServe(*metrics, nil); err != nil {
+				fmt.Fprintln(os.Stderr, "metrics server error:", err)
+			}
+		}()
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	<-sigCh
+
+	cancel()
+	_ = h.Close()
+}
+
+// loadOrGenerateKey loads a node key from path via Tendermint's NodeKey JSON
+// format and converts it to a libp2p identity using node.GetNodeKey. If path
+// is empty, a fresh ephemeral key is generated instead.
+func loadOrGenerateKey(path string) (crypto.PrivKey, error) {
+	if path == "" {
+		priv, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		return priv, err
+	}
+	nodeKey, err := cmtp2p.LoadOrGenNodeKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return node.GetNodeKey(nodeKey)
+}
+
+// registerMetrics exposes connected-peer count and DHT routing table size as
+// Prometheus gauges.
+func registerMetrics(h host.Host, kadDHT *dht.IpfsDHT) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{Name: "optimint_bootnode_connected_peers", Help: "Number of connected peers."},
+		func() float64 { return float64(len(h.Network().Peers())) },
+	))
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{Name: "optimint_bootnode_routing_table_size", Help: "Number of peers in the DHT routing table."},
+		func() float64 { return float64(kadDHT.RoutingTable().Size()) },
+	))
+}
+
+// stdLogger is a tiny Logger that writes to stdout, used because bootnode
+// has no access to a node's configured logger.
+type stdLogger struct{}
+
+func (l *stdLogger) Debug(msg string, keyvals ...interface{}) { fmt.Println(append([]interface{}{"DEBUG: " + msg}, keyvals...)...) }
+func (l *stdLogger) Info(msg string, keyvals ...interface{})  { fmt.Println(append([]interface{}{"INFO:  " + msg}, keyvals...)...) }
+func (l *stdLogger) Error(msg string, keyvals ...interface{}) { fmt.Println(append([]interface{}{"ERROR: " + msg}, keyvals...)...) }
@@ -0,0 +1,16 @@
+package config
+
+// P2PConfig stores configuration related to peer-to-peer networking.
+type P2PConfig struct {
+	// ListenAddress is a multiaddr on which libp2p host should listen to.
+	// If left empty, the default (/ip4/0.0.0.0/tcp/7676) is used.
+	ListenAddress string
+
+	// Seeds is a comma separated list of seed nodes to connect to on startup,
+	// encoded as libp2p multiaddrs (including the peer ID).
+	Seeds string
+
+	// EnableNAT enables libp2p's automatic NAT port mapping (UPnP/NAT-PMP),
+	// useful for nodes (e.g. bootnodes) that sit behind a home router.
+	EnableNAT bool
+}
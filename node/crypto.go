@@ -3,9 +3,10 @@ package node
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	p2p "github.com/cometbft/cometbft/p2p"
-	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p-core/crypto"
 )
 
 var (
@@ -13,19 +14,62 @@ var (
 	errUnsupportedKeyType = errors.New("unsupported key type")
 )
 
+// KeyUnmarshaler converts the raw bytes of a Tendermint/CometBFT private key
+// into the equivalent libp2p crypto.PrivKey.
+type KeyUnmarshaler func([]byte) (crypto.PrivKey, error)
+
+// keyUnmarshalers holds the converters GetNodeKey dispatches on, keyed by
+// the string a CometBFT PrivKey.Type() returns. It's seeded with the types
+// libp2p supports natively and can be extended via RegisterKeyType.
+var (
+	keyUnmarshalersMu sync.RWMutex
+	keyUnmarshalers   = map[string]KeyUnmarshaler{
+		"ed25519":   crypto.UnmarshalEd25519PrivateKey,
+		"secp256k1": crypto.UnmarshalSecp256k1PrivateKey,
+	}
+)
+
+// RegisterKeyType adds support for converting a Tendermint/CometBFT key of
+// the given type (as reported by its PrivKey.Type()) into a libp2p
+// crypto.PrivKey. This lets downstream projects wire in additional key
+// schemes, such as sr25519, without patching this package. Registering a
+// name that's already known replaces its converter. It panics if fn is nil,
+// since a registered nil converter would make GetNodeKey panic instead of
+// returning errUnsupportedKeyType the first time that key type is looked up.
+func RegisterKeyType(name string, fn KeyUnmarshaler) {
+	if fn == nil {
+		panic("node: RegisterKeyType called with a nil converter for " + name)
+	}
+	keyUnmarshalersMu.Lock()
+	defer keyUnmarshalersMu.Unlock()
+	keyUnmarshalers[name] = fn
+}
+
+// UnregisterKeyType removes a converter previously added with
+// RegisterKeyType, so GetNodeKey once again returns errUnsupportedKeyType
+// for that key type. It's a no-op if name was never registered.
+func UnregisterKeyType(name string) {
+	keyUnmarshalersMu.Lock()
+	defer keyUnmarshalersMu.Unlock()
+	delete(keyUnmarshalers, name)
+}
+
 // GetNodeKey creates libp2p private key from Tendermints NodeKey.
 func GetNodeKey(nodeKey *p2p.NodeKey) (crypto.PrivKey, error) {
 	if nodeKey == nil || nodeKey.PrivKey == nil {
 		return nil, errNilKey
 	}
-	switch nodeKey.PrivKey.Type() {
-	case "ed25519":
-		privKey, err := crypto.UnmarshalEd25519PrivateKey(nodeKey.PrivKey.Bytes())
-		if err != nil {
-			return nil, fmt.Errorf("error while node private key: %w", err)
-		}
-		return privKey, nil
-	default:
+
+	keyUnmarshalersMu.RLock()
+	unmarshal, ok := keyUnmarshalers[nodeKey.PrivKey.Type()]
+	keyUnmarshalersMu.RUnlock()
+	if !ok {
 		return nil, errUnsupportedKeyType
 	}
+
+	privKey, err := unmarshal(nodeKey.PrivKey.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error while node private key: %w", err)
+	}
+	return privKey, nil
 }
@@ -0,0 +1,98 @@
+package node
+
+import (
+	"testing"
+
+	cmtcrypto "github.com/cometbft/cometbft/crypto"
+	cmted25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cmtsecp256k1 "github.com/cometbft/cometbft/crypto/secp256k1"
+	"github.com/cometbft/cometbft/p2p"
+	libp2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/config"
+	optimintp2p "github.com/lazyledger/optimint/p2p"
+)
+
+func TestGetNodeKey(t *testing.T) {
+	cases := []struct {
+		name    string
+		privKey cmtcrypto.PrivKey
+	}{
+		{"ed25519", cmted25519.GenPrivKey()},
+		{"secp256k1", cmtsecp256k1.GenPrivKey()},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			nodeKey := &p2p.NodeKey{PrivKey: c.privKey}
+
+			libp2pKey, err := GetNodeKey(nodeKey)
+			require.NoError(t, err)
+			require.NotNil(t, libp2pKey)
+
+			// Round-trip the derived key through a real libp2p host and
+			// confirm its identity is exactly the peer.ID the rest of the
+			// network would compute from the same key material.
+			h, err := optimintp2p.NewHost(config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"}, libp2pKey)
+			require.NoError(t, err)
+			defer h.Close()
+
+			expectedID, err := peer.IDFromPrivateKey(libp2pKey)
+			require.NoError(t, err)
+			assert.Equal(t, expectedID, h.ID())
+		})
+	}
+}
+
+func TestGetNodeKeyNil(t *testing.T) {
+	_, err := GetNodeKey(nil)
+	assert.ErrorIs(t, err, errNilKey)
+
+	_, err = GetNodeKey(&p2p.NodeKey{})
+	assert.ErrorIs(t, err, errNilKey)
+}
+
+func TestGetNodeKeyUnsupportedType(t *testing.T) {
+	_, err := GetNodeKey(&p2p.NodeKey{PrivKey: renamedPrivKey{cmted25519.GenPrivKey(), "sr25519"}})
+	assert.ErrorIs(t, err, errUnsupportedKeyType)
+}
+
+func TestRegisterKeyType(t *testing.T) {
+	// Stands in for a downstream project wiring in a key scheme this
+	// package doesn't know about natively (e.g. CometBFT's sr25519),
+	// without needing to patch GetNodeKey itself.
+	RegisterKeyType("sr25519", libp2pcrypto.UnmarshalEd25519PrivateKey)
+	t.Cleanup(func() { UnregisterKeyType("sr25519") })
+
+	nodeKey := &p2p.NodeKey{PrivKey: renamedPrivKey{cmted25519.GenPrivKey(), "sr25519"}}
+
+	libp2pKey, err := GetNodeKey(nodeKey)
+	require.NoError(t, err)
+	assert.NotNil(t, libp2pKey)
+}
+
+func TestRegisterKeyTypeNilConverterPanics(t *testing.T) {
+	assert.Panics(t, func() { RegisterKeyType("sr25519", nil) })
+}
+
+func TestUnregisterKeyType(t *testing.T) {
+	RegisterKeyType("sr25519", libp2pcrypto.UnmarshalEd25519PrivateKey)
+	UnregisterKeyType("sr25519")
+
+	nodeKey := &p2p.NodeKey{PrivKey: renamedPrivKey{cmted25519.GenPrivKey(), "sr25519"}}
+	_, err := GetNodeKey(nodeKey)
+	assert.ErrorIs(t, err, errUnsupportedKeyType)
+}
+
+// renamedPrivKey wraps a real CometBFT PrivKey but reports a different
+// Type(), so tests can exercise GetNodeKey's dispatch without depending on
+// an actual unsupported or not-yet-released key implementation.
+type renamedPrivKey struct {
+	cmtcrypto.PrivKey
+	typ string
+}
+
+func (k renamedPrivKey) Type() string { return k.typ }
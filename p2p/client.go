@@ -0,0 +1,393 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/lazyledger/optimint/config"
+)
+
+// errNilKey is returned by NewClient when given a nil private key.
+var errNilKey = errors.New("private key can't be nil")
+
+// errNotStarted is returned by accessors that require Start to have been
+// called first.
+var errNotStarted = errors.New("client not started")
+
+// DefaultListenAddress is the default listen multiaddr used when
+// P2PConfig.ListenAddress is empty.
+const DefaultListenAddress = "/ip4/0.0.0.0/tcp/7676"
+
+// txTopicSuffix is appended to the chain ID to build the pubsub topic used
+// for transaction gossip.
+const txTopicSuffix = "-tx"
+
+// nodeInfoHandshakeTimeout bounds how long the nodeinfo handshake with a
+// newly connected peer is allowed to take before it's abandoned.
+const nodeInfoHandshakeTimeout = 10 * time.Second
+
+// Tx wraps a single gossiped transaction.
+type Tx struct {
+	Data []byte
+}
+
+// TxHandler is called for every Tx received over the transaction topic.
+type TxHandler func(*Tx)
+
+// Client is a P2P client, implemented with libp2p. It manages a libp2p host,
+// a Kademlia DHT used for peer discovery, and a gossipsub topic used to
+// exchange transactions between nodes of the same chain.
+type Client struct {
+	conf    config.P2PConfig
+	privKey crypto.PrivKey
+	chainID string
+	moniker string
+	logger  Logger
+
+	host host.Host
+	dht  *dht.IpfsDHT
+
+	pubsub    *pubsub.PubSub
+	txTopic   *pubsub.Topic
+	txSub     *pubsub.Subscription
+	txHandler TxHandler
+
+	nodeInfoMu   sync.RWMutex
+	peerNodeInfo map[peer.ID]NodeInfo
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	hostFactory func() (host.Host, error)
+}
+
+// ClientOption configures optional Client behavior not covered by
+// config.P2PConfig.
+type ClientOption func(*Client)
+
+// WithHostFactory overrides how the Client creates its libp2p host, instead
+// of the default (a real TCP/QUIC host via NewHost). This exists so that
+// packages like p2p/simulations can hand the Client an in-memory host
+// (e.g. from libp2p's mocknet) while still exercising real Client behavior.
+func WithHostFactory(factory func() (host.Host, error)) ClientOption {
+	return func(c *Client) {
+		c.hostFactory = factory
+	}
+}
+
+// WithMoniker sets the human-readable name this Client reports in its
+// NodeInfo. It has no effect on peer compatibility checks.
+func WithMoniker(moniker string) ClientOption {
+	return func(c *Client) {
+		c.moniker = moniker
+	}
+}
+
+// NewClient creates a new P2P client, ready to be started with Start.
+func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, logger Logger, opts ...ClientOption) (*Client, error) {
+	if privKey == nil {
+		return nil, errNilKey
+	}
+	c := &Client{
+		conf:         conf,
+		privKey:      privKey,
+		chainID:      chainID,
+		logger:       logger,
+		peerNodeInfo: make(map[peer.ID]NodeInfo),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Start creates the libp2p host, bootstraps the DHT against the configured
+// seeds, and joins the transaction gossip topic for this client's chain.
+func (c *Client) Start(ctx context.Context) (err error) {
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	// If anything below fails, cancel what we've started so far and wait
+	// for it to wind down instead of leaking goroutines tied to a Client
+	// whose Start the caller thinks never succeeded.
+	defer func() {
+		if err != nil {
+			c.cancel()
+			c.wg.Wait()
+		}
+	}()
+
+	factory := c.hostFactory
+	if factory == nil {
+		factory = func() (host.Host, error) { return NewHost(c.conf, c.privKey) }
+	}
+	h, err := factory()
+	if err != nil {
+		return fmt.Errorf("failed to create libp2p host: %w", err)
+	}
+	c.host = h
+
+	h.SetStreamHandler(NodeInfoProtocolID, c.handleNodeInfoStream)
+	h.Network().Notify(&network.NotifyBundle{
+		ConnectedF:    c.handlePeerConnected,
+		DisconnectedF: c.handlePeerDisconnected,
+	})
+
+	seeds := c.getSeedAddrInfo(c.conf.Seeds)
+	kadDHT, err := NewDHT(c.ctx, h, seeds, c.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create DHT: %w", err)
+	}
+	c.dht = kadDHT
+
+	// Tear down the DHT and host as soon as ctx is canceled, so canceling
+	// the caller's context alone releases everything Start set up. Close's
+	// own host.Close afterwards is then a no-op.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		<-c.ctx.Done()
+		_ = kadDHT.Close()
+		_ = h.Close()
+	}()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		Discover(c.ctx, h, kadDHT, c.rendezvous(), c.logger)
+	}()
+
+	ps, err := pubsub.NewGossipSub(c.ctx, h)
+	if err != nil {
+		return fmt.Errorf("failed to create gossipsub: %w", err)
+	}
+	c.pubsub = ps
+
+	topic, err := ps.Join(c.chainID + txTopicSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to join tx topic: %w", err)
+	}
+	c.txTopic = topic
+
+	sub, err := topic.Subscribe()
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to tx topic: %w", err)
+	}
+	c.txSub = sub
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.txLoop()
+	}()
+
+	return nil
+}
+
+// Close cancels the Client's context and waits for every goroutine spawned
+// by Start to observe the cancellation and exit before tearing down the
+// libp2p host. Callers that run many services alongside the Client (as
+// Rollkit does) can rely on Close never returning while background work is
+// still in flight.
+func (c *Client) Close() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+	if c.host != nil {
+		return c.host.Close()
+	}
+	return nil
+}
+
+// SetTxHandler sets the callback invoked for every Tx received on the gossip
+// topic. It must be called before Start to avoid missing early messages.
+func (c *Client) SetTxHandler(handler TxHandler) {
+	c.txHandler = handler
+}
+
+// Host returns the Client's libp2p host. It returns an error if the Client
+// hasn't been started yet.
+func (c *Client) Host() (host.Host, error) {
+	if c.host == nil {
+		return nil, errNotStarted
+	}
+	return c.host, nil
+}
+
+// GossipTx publishes data to the transaction topic for this client's chain.
+func (c *Client) GossipTx(ctx context.Context, data []byte) error {
+	return c.txTopic.Publish(ctx, data)
+}
+
+// NetInfo reports the Client's listen addresses plus the NodeInfo of every
+// peer that has completed the nodeinfo handshake, in a form suitable for
+// wiring straight into a JSON-RPC /net_info endpoint.
+func (c *Client) NetInfo() (NetInfo, error) {
+	if c.host == nil {
+		return NetInfo{}, errNotStarted
+	}
+
+	addrs := c.host.Addrs()
+	listenAddrs := make([]string, len(addrs))
+	for i, a := range addrs {
+		listenAddrs[i] = a.String()
+	}
+
+	c.nodeInfoMu.RLock()
+	defer c.nodeInfoMu.RUnlock()
+	peers := make([]PeerInfo, 0, len(c.peerNodeInfo))
+	for id, info := range c.peerNodeInfo {
+		peers = append(peers, PeerInfo{ID: id.String(), NodeInfo: info})
+	}
+
+	return NetInfo{ListenAddrs: listenAddrs, Peers: peers}, nil
+}
+
+// localNodeInfo builds the NodeInfo this Client presents to peers during the
+// nodeinfo handshake.
+func (c *Client) localNodeInfo() NodeInfo {
+	var listenAddr string
+	if addrs := c.host.Addrs(); len(addrs) > 0 {
+		listenAddr = addrs[0].String()
+	}
+	return NodeInfo{
+		ProtocolVersion: ProtocolVersion,
+		ChainID:         c.chainID,
+		Moniker:         c.moniker,
+		ListenAddr:      listenAddr,
+		Channels:        []string{c.chainID + txTopicSuffix},
+		SoftwareVersion: SoftwareVersion,
+	}
+}
+
+// handlePeerConnected is invoked by the libp2p host for every newly
+// established connection. It kicks off the nodeinfo handshake with the
+// remote peer in its own goroutine so it doesn't block connection setup.
+func (c *Client) handlePeerConnected(_ network.Network, conn network.Conn) {
+	remote := conn.RemotePeer()
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.requestNodeInfo(remote)
+	}()
+}
+
+// handlePeerDisconnected is invoked by the libp2p host whenever a
+// connection is torn down. It drops the peer's NodeInfo once no connection
+// to it remains, so NetInfo never reports a peer as connected after it's
+// actually left.
+func (c *Client) handlePeerDisconnected(net network.Network, conn network.Conn) {
+	p := conn.RemotePeer()
+	if net.Connectedness(p) == network.Connected {
+		// Another connection to the same peer is still open.
+		return
+	}
+	c.nodeInfoMu.Lock()
+	delete(c.peerNodeInfo, p)
+	c.nodeInfoMu.Unlock()
+}
+
+// requestNodeInfo opens a stream to p under NodeInfoProtocolID, exchanges
+// NodeInfo, and disconnects p if it turns out to be running an incompatible
+// protocol version or chain ID.
+func (c *Client) requestNodeInfo(p peer.ID) {
+	ctx, cancel := context.WithTimeout(c.ctx, nodeInfoHandshakeTimeout)
+	defer cancel()
+
+	s, err := c.host.NewStream(ctx, p, NodeInfoProtocolID)
+	if err != nil {
+		c.logger.Debug("failed to open nodeinfo stream", "peer", p, "error", err)
+		return
+	}
+	defer s.Close()
+
+	if err := writeNodeInfo(s, c.localNodeInfo()); err != nil {
+		c.logger.Debug("failed to send nodeinfo", "peer", p, "error", err)
+		return
+	}
+
+	remote, err := readNodeInfo(s)
+	if err != nil {
+		c.logger.Error("failed to read nodeinfo", "peer", p, "error", err)
+		return
+	}
+
+	c.acceptNodeInfo(p, remote)
+}
+
+// handleNodeInfoStream is the NodeInfoProtocolID stream handler for
+// incoming connections: it mirrors requestNodeInfo's exchange from the
+// other side (read first, then respond) so either peer can dial it.
+func (c *Client) handleNodeInfoStream(s network.Stream) {
+	defer s.Close()
+	remotePeer := s.Conn().RemotePeer()
+
+	remote, err := readNodeInfo(s)
+	if err != nil {
+		c.logger.Error("failed to read nodeinfo", "peer", remotePeer, "error", err)
+		return
+	}
+
+	if err := writeNodeInfo(s, c.localNodeInfo()); err != nil {
+		c.logger.Debug("failed to send nodeinfo", "peer", remotePeer, "error", err)
+		return
+	}
+
+	c.acceptNodeInfo(remotePeer, remote)
+}
+
+// acceptNodeInfo records remote's NodeInfo for p if it's compatible with
+// this Client's own, or disconnects p if it isn't. Gossipsub and the DHT
+// do not wait for this check, so an incompatible peer can take part in
+// both until it is disconnected here.
+func (c *Client) acceptNodeInfo(p peer.ID, remote NodeInfo) {
+	if err := c.localNodeInfo().Compatible(remote); err != nil {
+		c.logger.Error("rejecting incompatible peer", "peer", p, "error", err)
+		if closeErr := c.host.Network().ClosePeer(p); closeErr != nil {
+			c.logger.Debug("failed to close incompatible peer", "peer", p, "error", closeErr)
+		}
+		return
+	}
+
+	c.nodeInfoMu.Lock()
+	c.peerNodeInfo[p] = remote
+	c.nodeInfoMu.Unlock()
+}
+
+// rendezvous returns the DHT advertisement string used to find other peers
+// of the same chain.
+func (c *Client) rendezvous() string {
+	return "optimint-discovery-" + c.chainID
+}
+
+// getSeedAddrInfo parses the client's configured seed string into AddrInfos.
+func (c *Client) getSeedAddrInfo(seeds string) []peer.AddrInfo {
+	return ParseSeeds(seeds, c.logger)
+}
+
+// txLoop reads messages off txSub until the client's context is canceled,
+// forwarding each one to the registered TxHandler.
+func (c *Client) txLoop() {
+	for {
+		msg, err := c.txSub.Next(c.ctx)
+		if err != nil {
+			return
+		}
+		if msg.ReceivedFrom == c.host.ID() {
+			continue
+		}
+		if c.txHandler != nil {
+			c.txHandler(&Tx{Data: msg.Data})
+		}
+	}
+}
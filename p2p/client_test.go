@@ -4,6 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"io"
+	mathrand "math/rand"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -14,6 +17,7 @@ import (
 	"github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 
 	"github.com/lazyledger/optimint/config"
 )
@@ -66,6 +70,71 @@ func TestClientStartup(t *testing.T) {
 	assert.NoError(err)
 }
 
+// hostDescr describes one node in a test network built by startTestNetwork:
+// which already-created nodes it should be seeded with on startup (by
+// index), which chain it belongs to, and whether its identity should be a
+// fresh crypto/rand key or a deterministic one (for reproducible runs).
+type hostDescr struct {
+	conns   []int
+	chainID string
+	realKey bool
+}
+
+// testClients is the network of Clients built by startTestNetwork, indexed
+// the same way as the hostDescr map passed to it.
+type testClients []*Client
+
+// WaitForDHT gives every client's DHT routing table and Discover loop a
+// chance to converge, so peers that were only indirectly seeded (reachable
+// through the DHT rather than a direct connection) have time to find each
+// other via rendezvous.
+func (c testClients) WaitForDHT() {
+	time.Sleep(3 * time.Second)
+}
+
+// startTestNetwork creates n Clients on chainID "TestNetwork" (unless
+// overridden per-node in descs), connects each one directly to the nodes
+// listed in its hostDescr.conns, starts it, and returns all of them indexed
+// by their position. Nodes are created in index order, so conns may only
+// reference lower indices.
+func startTestNetwork(ctx context.Context, t *testing.T, n int, descs map[int]hostDescr, logger Logger) testClients {
+	t.Helper()
+
+	clients := make(testClients, n)
+	for i := 0; i < n; i++ {
+		desc := descs[i]
+
+		chainID := desc.chainID
+		if chainID == "" {
+			chainID = "TestNetwork"
+		}
+
+		keyReader := io.Reader(rand.Reader)
+		if !desc.realKey {
+			keyReader = mathrand.New(mathrand.NewSource(int64(i)))
+		}
+		privKey, _, err := crypto.GenerateEd25519Key(keyReader)
+		require.NoError(t, err)
+
+		seeds := make([]string, 0, len(desc.conns))
+		for _, conn := range desc.conns {
+			seedHost := clients[conn].host
+			seeds = append(seeds, fmt.Sprintf("%s/p2p/%s", seedHost.Addrs()[0], seedHost.ID()))
+		}
+
+		conf := config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/0",
+			Seeds:         strings.Join(seeds, ","),
+		}
+		client, err := NewClient(conf, privKey, chainID, logger)
+		require.NoError(t, err)
+		require.NoError(t, client.Start(ctx))
+		clients[i] = client
+	}
+
+	return clients
+}
+
 func TestBootstrapping(t *testing.T) {
 	_ = log.SetLogLevel("dht", "INFO")
 	//log.SetDebugLogging()
@@ -161,6 +230,153 @@ func TestGossiping(t *testing.T) {
 	wg.Wait()
 }
 
+func TestContextCancellationStopsGoroutines(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := &TestLogger{t}
+
+	// Snapshot the goroutines already running (test runner, other tests'
+	// leftovers, etc.) so Find below only reports ones we spawn here.
+	baseline := goleak.IgnoreCurrent()
+
+	const n = 3
+	ctx, cancel := context.WithCancel(context.Background())
+	clients := make([]*Client, n)
+	for i := 0; i < n; i++ {
+		privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		client, err := NewClient(config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"}, privKey, "TestChain", logger)
+		require.NoError(err)
+		require.NoError(client.Start(ctx))
+		clients[i] = client
+	}
+
+	// Canceling the shared parent context, without calling Close, must be
+	// enough: every goroutine Start spawned derives its lifetime from ctx
+	// and should exit on its own within a small deadline.
+	cancel()
+
+	assert.Eventually(func() bool {
+		return goleak.Find(baseline) == nil
+	}, 5*time.Second, 50*time.Millisecond, "goroutines spawned by Start outlived context cancellation")
+
+	for _, client := range clients {
+		assert.NoError(client.Close())
+	}
+}
+
+// connectClients connects a's host directly to b's, bypassing the DHT, so
+// the nodeinfo handshake can be exercised without waiting on rendezvous.
+func connectClients(ctx context.Context, t *testing.T, a, b *Client) {
+	t.Helper()
+	aHost, err := a.Host()
+	require.NoError(t, err)
+	bHost, err := b.Host()
+	require.NoError(t, err)
+
+	require.NoError(t, aHost.Connect(ctx, peer.AddrInfo{ID: bHost.ID(), Addrs: bHost.Addrs()}))
+}
+
+func newTestClient(t *testing.T, ctx context.Context, chainID string, logger Logger) *Client {
+	t.Helper()
+	privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	client, err := NewClient(config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"}, privKey, chainID, logger)
+	require.NoError(t, err)
+	require.NoError(t, client.Start(ctx))
+	return client
+}
+
+func TestNodeInfoHandshakeAccepted(t *testing.T) {
+	assert := assert.New(t)
+	logger := &TestLogger{t}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newTestClient(t, ctx, "TestChain", logger)
+	defer a.Close()
+	b := newTestClient(t, ctx, "TestChain", logger)
+	defer b.Close()
+
+	connectClients(ctx, t, a, b)
+
+	aHost, _ := a.Host()
+	bHost, _ := b.Host()
+
+	assert.Eventually(func() bool {
+		info, err := a.NetInfo()
+		if err != nil || len(info.Peers) != 1 {
+			return false
+		}
+		return info.Peers[0].ID == bHost.ID().String() && info.Peers[0].NodeInfo.ChainID == "TestChain"
+	}, 5*time.Second, 50*time.Millisecond, "a never recorded b's NodeInfo")
+
+	assert.Eventually(func() bool {
+		info, err := b.NetInfo()
+		return err == nil && len(info.Peers) == 1 && info.Peers[0].ID == aHost.ID().String()
+	}, 5*time.Second, 50*time.Millisecond, "b never recorded a's NodeInfo")
+}
+
+func TestNodeInfoRemovedOnDisconnect(t *testing.T) {
+	assert := assert.New(t)
+	logger := &TestLogger{t}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newTestClient(t, ctx, "TestChain", logger)
+	defer a.Close()
+	b := newTestClient(t, ctx, "TestChain", logger)
+	defer b.Close()
+
+	connectClients(ctx, t, a, b)
+
+	assert.Eventually(func() bool {
+		info, err := a.NetInfo()
+		return err == nil && len(info.Peers) == 1
+	}, 5*time.Second, 50*time.Millisecond, "a never recorded b's NodeInfo")
+
+	aHost, _ := a.Host()
+	bHost, _ := b.Host()
+	require.NoError(t, aHost.Network().ClosePeer(bHost.ID()))
+
+	assert.Eventually(func() bool {
+		info, err := a.NetInfo()
+		return err == nil && len(info.Peers) == 0
+	}, 5*time.Second, 50*time.Millisecond, "a still reported b's NodeInfo after disconnect")
+}
+
+func TestNodeInfoHandshakeRejectsChainMismatch(t *testing.T) {
+	assert := assert.New(t)
+	logger := &MockLogger{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newTestClient(t, ctx, "ChainA", logger)
+	defer a.Close()
+	b := newTestClient(t, ctx, "ChainB", logger)
+	defer b.Close()
+
+	connectClients(ctx, t, a, b)
+
+	aHost, _ := a.Host()
+
+	assert.Eventually(func() bool {
+		return len(aHost.Network().Peers()) == 0
+	}, 5*time.Second, 50*time.Millisecond, "chain-mismatched peer was never disconnected")
+
+	found := false
+	for _, e := range logger.err {
+		if strings.Contains(e, "rejecting incompatible peer") {
+			found = true
+			break
+		}
+	}
+	assert.True(found, "expected a logged error rejecting the incompatible peer, got: %v", logger.err)
+}
+
 func TestSeedStringParsing(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,144 @@
+package p2p
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	libp2p "github.com/libp2p/go-libp2p"
+	discovery "github.com/libp2p/go-libp2p-discovery"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/multiformats/go-multiaddr"
+
+	"github.com/lazyledger/optimint/config"
+)
+
+// discoveryRefreshInterval is how often Discover re-advertises and
+// re-searches for peers under the rendezvous string.
+const discoveryRefreshInterval = 1 * time.Minute
+
+// NewHost creates a libp2p host using privKey as its identity and listening
+// on the address configured in conf (or DefaultListenAddress if empty).
+// It carries no pubsub or chain-specific behavior, so it can be reused by
+// both the full p2p Client and DHT-only tools like cmd/bootnode.
+func NewHost(conf config.P2PConfig, privKey crypto.PrivKey) (host.Host, error) {
+	addr := conf.ListenAddress
+	if addr == "" {
+		addr = DefaultListenAddress
+	}
+	listenAddr, err := multiaddr.NewMultiaddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	opts := []libp2p.Option{libp2p.ListenAddrs(listenAddr), libp2p.Identity(privKey)}
+	if conf.EnableNAT {
+		opts = append(opts, libp2p.NATPortMap())
+	}
+	return libp2p.New(opts...)
+}
+
+// NewDHT creates a Kademlia DHT on top of h in server mode and connects to
+// the given seed peers. The returned DHT is already bootstrapped; callers
+// are responsible for eventually closing it via Close().
+func NewDHT(ctx context.Context, h host.Host, seeds []peer.AddrInfo, logger Logger) (*dht.IpfsDHT, error) {
+	kadDHT, err := dht.New(ctx, h, dht.Mode(dht.ModeServer))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := kadDHT.Bootstrap(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, seed := range seeds {
+		if seed.ID == h.ID() {
+			continue
+		}
+		if err := h.Connect(ctx, seed); err != nil {
+			logger.Error("failed to connect to seed", "peer", seed, "error", err)
+			continue
+		}
+		logger.Info("connected to seed", "peer", seed)
+	}
+
+	return kadDHT, nil
+}
+
+// Discover advertises rendezvous under kadDHT and continuously searches for
+// other peers advertising the same string, connecting to any that aren't
+// already part of h's network. It blocks until ctx is canceled, so it's
+// meant to be run in its own goroutine.
+func Discover(ctx context.Context, h host.Host, kadDHT *dht.IpfsDHT, rendezvous string, logger Logger) {
+	routingDisc := discovery.NewRoutingDiscovery(kadDHT)
+	discovery.Advertise(ctx, routingDisc, rendezvous)
+
+	findPeers := func() {
+		peers, err := discovery.FindPeers(ctx, routingDisc, rendezvous)
+		if err != nil {
+			logger.Error("peer discovery failed", "error", err)
+			return
+		}
+		for _, p := range peers {
+			if p.ID == h.ID() || len(p.Addrs) == 0 {
+				continue
+			}
+			if h.Network().Connectedness(p.ID) == network.Connected {
+				continue
+			}
+			if err := h.Connect(ctx, p); err != nil {
+				logger.Debug("failed to connect to discovered peer", "peer", p, "error", err)
+				continue
+			}
+			logger.Info("connected to discovered peer", "peer", p)
+		}
+	}
+
+	// Search once right away instead of waiting a full
+	// discoveryRefreshInterval, so a node that starts after its peers have
+	// already advertised doesn't sit disconnected for up to a minute.
+	findPeers()
+
+	ticker := time.NewTicker(discoveryRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			findPeers()
+		}
+	}
+}
+
+// ParseSeeds parses a comma-separated list of multiaddr-encoded seed peers,
+// logging and skipping (rather than failing on) any entries that can't be
+// parsed so that a single bad seed doesn't take down the rest.
+func ParseSeeds(seeds string, logger Logger) []peer.AddrInfo {
+	infos := []peer.AddrInfo{}
+	if seeds == "" {
+		return infos
+	}
+	for _, s := range strings.Split(seeds, ",") {
+		if s == "" {
+			logger.Error("empty seed address")
+			continue
+		}
+		ma, err := multiaddr.NewMultiaddr(s)
+		if err != nil {
+			logger.Error("failed to parse seed address", "address", s, "error", err)
+			continue
+		}
+		ai, err := peer.AddrInfoFromP2pAddr(ma)
+		if err != nil {
+			logger.Error("failed to derive peer info from seed address", "address", s, "error", err)
+			continue
+		}
+		infos = append(infos, *ai)
+	}
+	return infos
+}
@@ -0,0 +1,10 @@
+package p2p
+
+// Logger is a minimal logging interface used throughout the p2p package.
+// It intentionally mirrors Tendermint's log.Logger subset so that
+// implementations can be shared with the rest of the node.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
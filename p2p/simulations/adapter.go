@@ -0,0 +1,49 @@
+// Package simulations provides an in-process network simulation harness for
+// testing p2p behavior (gossip propagation, DHT re-bootstrap, seed
+// reconnection) at a scale that the table-driven tests in package p2p
+// can't reach, modeled on go-ethereum's swarm p2p/simulations package.
+package simulations
+
+import (
+	"context"
+
+	"github.com/lazyledger/optimint/p2p"
+)
+
+// NodeConfig describes a single simulated node before it's started.
+type NodeConfig struct {
+	// ID uniquely identifies the node within a Network.
+	ID string
+	// ChainID is the chain identifier the node's p2p.Client advertises and
+	// gossips transactions under.
+	ChainID string
+}
+
+// Node is a running simulated node, wrapping a real p2p.Client so that
+// gossip, DHT and seed-reconnect behavior under simulation matches
+// production exactly.
+type Node interface {
+	// Config returns the configuration the node was created with.
+	Config() NodeConfig
+	// Client returns the underlying p2p.Client.
+	Client() *p2p.Client
+	// Start starts the node's p2p.Client.
+	Start(ctx context.Context) error
+	// Stop stops the node's p2p.Client.
+	Stop() error
+}
+
+// Adapter creates and connects simulated Nodes. Different adapters trade off
+// fidelity for speed/scale: an in-proc adapter can run hundreds of nodes in
+// a single test process, while an exec adapter spawns one OS process per
+// node for full-fidelity (but far more expensive) testing.
+type Adapter interface {
+	// Name identifies the adapter, for logging/debugging.
+	Name() string
+	// NewNode creates (but does not start) a new Node from conf.
+	NewNode(conf NodeConfig) (Node, error)
+	// Connect establishes a connection between two previously created nodes.
+	Connect(ctx context.Context, one, other Node) error
+	// Disconnect tears down the connection between two nodes, if any.
+	Disconnect(one, other Node) error
+}
@@ -0,0 +1,52 @@
+package simulations
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lazyledger/optimint/p2p"
+)
+
+// errExecAdapterNotImplemented is returned by ExecAdapter's NewNode, Connect
+// and Disconnect until
+// the fork/exec harness (spawning one real OS process per simulated node,
+// communicating over a control socket) is implemented.
+var errExecAdapterNotImplemented = errors.New("exec adapter not implemented yet")
+
+// ExecAdapter is a placeholder Adapter that will fork/exec a helper binary
+// per simulated node, for tests that need full process isolation (e.g. to
+// simulate a node crashing and losing in-memory state) rather than the
+// speed of InprocAdapter.
+type ExecAdapter struct {
+	// BinPath is the path to the helper binary each node will be exec'd
+	// from; it's expected to bring up a single p2p.Client and speak a
+	// control protocol back to the parent process.
+	BinPath string
+	logger  p2p.Logger
+}
+
+// NewExecAdapter creates an ExecAdapter that will exec binPath for each
+// node.
+func NewExecAdapter(binPath string, logger p2p.Logger) *ExecAdapter {
+	return &ExecAdapter{BinPath: binPath, logger: logger}
+}
+
+// Name implements Adapter.
+func (a *ExecAdapter) Name() string {
+	return "exec"
+}
+
+// NewNode implements Adapter.
+func (a *ExecAdapter) NewNode(conf NodeConfig) (Node, error) {
+	return nil, errExecAdapterNotImplemented
+}
+
+// Connect implements Adapter.
+func (a *ExecAdapter) Connect(ctx context.Context, one, other Node) error {
+	return errExecAdapterNotImplemented
+}
+
+// Disconnect implements Adapter.
+func (a *ExecAdapter) Disconnect(one, other Node) error {
+	return errExecAdapterNotImplemented
+}
@@ -0,0 +1,134 @@
+package simulations
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/lazyledger/optimint/p2p"
+)
+
+// MockerConfig controls how a Mocker drives churn against a Network.
+type MockerConfig struct {
+	// Interval is how often the Mocker performs one churn step.
+	Interval time.Duration
+	// NodeCount is how many nodes the Mocker creates before it starts
+	// churning.
+	NodeCount int
+	// MaxNodes caps how many nodes JoinProbability is allowed to grow the
+	// network to. 0 means no cap: joins continue without limit.
+	MaxNodes int
+	// JoinProbability is the chance (0-1) that a given step adds a new node
+	// to the network instead of acting on the existing ones.
+	JoinProbability float64
+	// LeaveProbability is the chance (0-1), evaluated after JoinProbability,
+	// that a given step stops a random existing node instead of connecting
+	// or disconnecting a pair.
+	LeaveProbability float64
+	// PartitionProbability is the chance (0-1), once a step falls through to
+	// acting on a pair of nodes, that it disconnects a random pair instead
+	// of connecting one.
+	PartitionProbability float64
+}
+
+// Mocker drives random join/leave/partition churn against a Network on a
+// schedule, so tests can stress gossip propagation, DHT re-bootstrap and
+// seed reconnection under conditions the small table-driven p2p tests
+// can't reach.
+type Mocker struct {
+	network *Network
+	conf    MockerConfig
+	logger  p2p.Logger
+	rand    *rand.Rand
+	nextID  int
+}
+
+// NewMocker creates a Mocker that will churn network according to conf.
+func NewMocker(network *Network, conf MockerConfig, logger p2p.Logger) *Mocker {
+	return &Mocker{
+		network: network,
+		conf:    conf,
+		logger:  logger,
+		rand:    rand.New(rand.NewSource(1)),
+	}
+}
+
+// Run seeds the Network with conf.NodeCount nodes, then performs one churn
+// step every conf.Interval until ctx is canceled.
+func (m *Mocker) Run(ctx context.Context) error {
+	for i := 0; i < m.conf.NodeCount; i++ {
+		id := nodeID(i)
+		if _, err := m.network.AddNode(ctx, NodeConfig{ID: id, ChainID: "mocker"}); err != nil {
+			return err
+		}
+	}
+	m.nextID = m.conf.NodeCount
+
+	ticker := time.NewTicker(m.conf.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.step(ctx)
+		}
+	}
+}
+
+// step performs a single random churn action: joining a new node (with
+// JoinProbability), having an existing one leave (with LeaveProbability),
+// or otherwise acting on a pair of existing nodes -- disconnecting them
+// (with PartitionProbability) or connecting them.
+func (m *Mocker) step(ctx context.Context) {
+	ids := m.network.NodeIDs()
+
+	if r := m.rand.Float64(); r < m.conf.JoinProbability {
+		if m.conf.MaxNodes != 0 && len(ids) >= m.conf.MaxNodes {
+			return
+		}
+		id := nodeID(m.nextID)
+		m.nextID++
+		if _, err := m.network.AddNode(ctx, NodeConfig{ID: id, ChainID: "mocker"}); err != nil {
+			m.logger.Debug("mocker: join failed", "node", id, "error", err)
+		}
+		return
+	} else if r < m.conf.JoinProbability+m.conf.LeaveProbability {
+		if len(ids) == 0 {
+			return
+		}
+		id := ids[m.rand.Intn(len(ids))]
+		if err := m.network.Stop(id); err != nil {
+			m.logger.Debug("mocker: leave failed", "node", id, "error", err)
+		}
+		return
+	}
+
+	if len(ids) < 2 {
+		return
+	}
+
+	one := ids[m.rand.Intn(len(ids))]
+	other := ids[m.rand.Intn(len(ids))]
+	if one == other {
+		return
+	}
+
+	if m.rand.Float64() < m.conf.PartitionProbability {
+		if err := m.network.Disconnect(one, other); err != nil {
+			m.logger.Debug("mocker: disconnect failed", "one", one, "other", other, "error", err)
+		}
+		return
+	}
+
+	if err := m.network.Connect(ctx, one, other); err != nil {
+		m.logger.Debug("mocker: connect failed", "one", one, "other", other, "error", err)
+	}
+}
+
+// nodeID generates a deterministic node ID for the i-th mocker-created node.
+func nodeID(i int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	return "node-" + string(letters[i%len(letters)]) + string(rune('0'+i/len(letters)))
+}
@@ -0,0 +1,246 @@
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/lazyledger/optimint/p2p"
+)
+
+// EventType categorizes a Network event.
+type EventType string
+
+const (
+	// EventTypeConnect is emitted whenever two nodes become connected.
+	EventTypeConnect EventType = "connect"
+	// EventTypeDisconnect is emitted whenever two nodes become disconnected.
+	EventTypeDisconnect EventType = "disconnect"
+	// EventTypeMessage is emitted whenever a node observes a gossiped Tx.
+	EventTypeMessage EventType = "message"
+)
+
+// Event describes a single topology or message event observed on a Network.
+// It's emitted on the channel returned by Network.Events so that tests (and
+// a Mocker) can watch gossip propagation, DHT re-bootstrap, and seed
+// reconnection as they happen.
+type Event struct {
+	Type EventType `json:"type"`
+	Node string    `json:"node"`
+	Peer string    `json:"peer,omitempty"`
+	Data []byte    `json:"data,omitempty"`
+}
+
+// connKey builds a canonical, order-independent key for a node pair so a
+// connection and its reverse are tracked as the same edge.
+func connKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "|" + b
+}
+
+// Network is a simulated p2p network: a set of Nodes created through an
+// Adapter, plus the connections between them. It's the programmatic
+// interface tests and a Mocker use to drive topology changes.
+type Network struct {
+	adapter Adapter
+	logger  p2p.Logger
+
+	mu    sync.Mutex
+	nodes map[string]Node
+	conns map[string]ConnSnapshot
+
+	events chan Event
+}
+
+// NewNetwork creates an empty Network backed by adapter.
+func NewNetwork(adapter Adapter, logger p2p.Logger) *Network {
+	return &Network{
+		adapter: adapter,
+		logger:  logger,
+		nodes:   make(map[string]Node),
+		conns:   make(map[string]ConnSnapshot),
+		events:  make(chan Event, 256),
+	}
+}
+
+// Events returns the channel on which connect/disconnect/message events are
+// published. The channel is never closed; callers should stop reading when
+// they're done with the Network.
+func (n *Network) Events() <-chan Event {
+	return n.events
+}
+
+// AddNode creates and starts a new node from conf.
+func (n *Network) AddNode(ctx context.Context, conf NodeConfig) (Node, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.nodes[conf.ID]; ok {
+		return nil, fmt.Errorf("node %q already exists", conf.ID)
+	}
+
+	node, err := n.adapter.NewNode(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create node %q: %w", conf.ID, err)
+	}
+
+	// Wired before Start so no early Tx is missed, matching p2p.Client's own
+	// SetTxHandler contract.
+	node.Client().SetTxHandler(func(tx *p2p.Tx) {
+		n.emit(Event{Type: EventTypeMessage, Node: conf.ID, Data: tx.Data})
+	})
+
+	if err := node.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start node %q: %w", conf.ID, err)
+	}
+
+	n.nodes[conf.ID] = node
+	return node, nil
+}
+
+// Connect connects the two named nodes via the Network's adapter.
+func (n *Network) Connect(ctx context.Context, one, other string) error {
+	a, b, err := n.getPair(one, other)
+	if err != nil {
+		return err
+	}
+
+	if err := n.adapter.Connect(ctx, a, b); err != nil {
+		return fmt.Errorf("failed to connect %q and %q: %w", one, other, err)
+	}
+
+	n.mu.Lock()
+	n.conns[connKey(one, other)] = ConnSnapshot{One: one, Other: other}
+	n.mu.Unlock()
+
+	n.emit(Event{Type: EventTypeConnect, Node: one, Peer: other})
+	return nil
+}
+
+// Disconnect tears down the connection between the two named nodes.
+func (n *Network) Disconnect(one, other string) error {
+	a, b, err := n.getPair(one, other)
+	if err != nil {
+		return err
+	}
+
+	if err := n.adapter.Disconnect(a, b); err != nil {
+		return fmt.Errorf("failed to disconnect %q and %q: %w", one, other, err)
+	}
+
+	n.mu.Lock()
+	delete(n.conns, connKey(one, other))
+	n.mu.Unlock()
+
+	n.emit(Event{Type: EventTypeDisconnect, Node: one, Peer: other})
+	return nil
+}
+
+// Stop stops the named node and drops its connections from the Network's
+// record. No disconnect events are emitted for those connections.
+func (n *Network) Stop(id string) error {
+	n.mu.Lock()
+	node, ok := n.nodes[id]
+	if ok {
+		delete(n.nodes, id)
+		for key, conn := range n.conns {
+			if conn.One == id || conn.Other == id {
+				delete(n.conns, key)
+			}
+		}
+	}
+	n.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("node %q not found", id)
+	}
+	return node.Stop()
+}
+
+func (n *Network) getPair(one, other string) (Node, Node, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	a, ok := n.nodes[one]
+	if !ok {
+		return nil, nil, fmt.Errorf("node %q not found", one)
+	}
+	b, ok := n.nodes[other]
+	if !ok {
+		return nil, nil, fmt.Errorf("node %q not found", other)
+	}
+	return a, b, nil
+}
+
+func (n *Network) emit(e Event) {
+	select {
+	case n.events <- e:
+	default:
+		n.logger.Error("dropped simulation event, events channel full", "event", e)
+	}
+}
+
+// NodeIDs returns the IDs of all nodes currently in the Network.
+func (n *Network) NodeIDs() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ids := make([]string, 0, len(n.nodes))
+	for id := range n.nodes {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Snapshot is a serializable description of a Network's topology, suitable
+// for saving to disk and later restoring with Load.
+type Snapshot struct {
+	Nodes []NodeConfig   `json:"nodes"`
+	Conns []ConnSnapshot `json:"conns"`
+}
+
+// ConnSnapshot describes one edge in a Snapshot.
+type ConnSnapshot struct {
+	One   string `json:"one"`
+	Other string `json:"other"`
+}
+
+// Snapshot captures the Network's current nodes and connections as JSON.
+func (n *Network) Snapshot() ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	snap := Snapshot{}
+	for _, node := range n.nodes {
+		snap.Nodes = append(snap.Nodes, node.Config())
+	}
+	for _, conn := range n.conns {
+		snap.Conns = append(snap.Conns, conn)
+	}
+
+	return json.Marshal(snap)
+}
+
+// Load recreates a Network's topology from a Snapshot produced by Snapshot,
+// creating and connecting nodes via the Network's adapter.
+func (n *Network) Load(ctx context.Context, data []byte) error {
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	for _, conf := range snap.Nodes {
+		if _, err := n.AddNode(ctx, conf); err != nil {
+			return err
+		}
+	}
+	for _, conn := range snap.Conns {
+		if err := n.Connect(ctx, conn.One, conn.Other); err != nil {
+			return err
+		}
+	}
+	return nil
+}
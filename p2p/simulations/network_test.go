@@ -0,0 +1,134 @@
+package simulations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLogger logs to the testing.T, the same convention p2p's own tests use.
+type testLogger struct{ t *testing.T }
+
+func (l *testLogger) Debug(msg string, keyvals ...interface{}) {
+	l.t.Helper()
+	l.t.Log(append([]interface{}{"DEBUG: " + msg}, keyvals...)...)
+}
+
+func (l *testLogger) Info(msg string, keyvals ...interface{}) {
+	l.t.Helper()
+	l.t.Log(append([]interface{}{"INFO:  " + msg}, keyvals...)...)
+}
+
+func (l *testLogger) Error(msg string, keyvals ...interface{}) {
+	l.t.Helper()
+	l.t.Log(append([]interface{}{"ERROR: " + msg}, keyvals...)...)
+}
+
+// nextEvent reads the next event off net's Events channel, failing the test
+// if none arrives within 5 seconds.
+func nextEvent(t *testing.T, net *Network) Event {
+	t.Helper()
+	select {
+	case e := <-net.Events():
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for network event")
+		return Event{}
+	}
+}
+
+func TestInprocNetworkConnectAndDisconnect(t *testing.T) {
+	logger := &testLogger{t}
+	net := NewNetwork(NewInprocAdapter(logger), logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := net.AddNode(ctx, NodeConfig{ID: "a", ChainID: "sim"})
+	require.NoError(t, err)
+	_, err = net.AddNode(ctx, NodeConfig{ID: "b", ChainID: "sim"})
+	require.NoError(t, err)
+
+	require.NoError(t, net.Connect(ctx, "a", "b"))
+	connectEvent := nextEvent(t, net)
+	assert.Equal(t, EventTypeConnect, connectEvent.Type)
+	assert.Equal(t, "a", connectEvent.Node)
+	assert.Equal(t, "b", connectEvent.Peer)
+
+	require.NoError(t, net.Disconnect("a", "b"))
+	disconnectEvent := nextEvent(t, net)
+	assert.Equal(t, EventTypeDisconnect, disconnectEvent.Type)
+	assert.Equal(t, "a", disconnectEvent.Node)
+	assert.Equal(t, "b", disconnectEvent.Peer)
+}
+
+func TestInprocNetworkMessageEvent(t *testing.T) {
+	logger := &testLogger{t}
+	net := NewNetwork(NewInprocAdapter(logger), logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, err := net.AddNode(ctx, NodeConfig{ID: "a", ChainID: "sim"})
+	require.NoError(t, err)
+	_, err = net.AddNode(ctx, NodeConfig{ID: "b", ChainID: "sim"})
+	require.NoError(t, err)
+
+	require.NoError(t, net.Connect(ctx, "a", "b"))
+	_ = nextEvent(t, net) // the connect event
+
+	// Pubsub needs a moment to propagate subscription info before a
+	// gossiped Tx is guaranteed delivery, same as package p2p's own tests.
+	time.Sleep(1 * time.Second)
+
+	require.NoError(t, a.Client().GossipTx(ctx, []byte("hello")))
+
+	msgEvent := nextEvent(t, net)
+	assert.Equal(t, EventTypeMessage, msgEvent.Type)
+	assert.Equal(t, "b", msgEvent.Node)
+	assert.Equal(t, []byte("hello"), msgEvent.Data)
+}
+
+func TestMockerJoinsUpToMaxNodes(t *testing.T) {
+	logger := &testLogger{t}
+	net := NewNetwork(NewInprocAdapter(logger), logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewMocker(net, MockerConfig{
+		Interval:        10 * time.Millisecond,
+		NodeCount:       2,
+		MaxNodes:        4,
+		JoinProbability: 1,
+	}, logger)
+
+	go func() { _ = m.Run(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		return len(net.NodeIDs()) == 4
+	}, 5*time.Second, 20*time.Millisecond, "mocker never joined nodes up to MaxNodes")
+}
+
+func TestMockerLeaveShrinksNetwork(t *testing.T) {
+	logger := &testLogger{t}
+	net := NewNetwork(NewInprocAdapter(logger), logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewMocker(net, MockerConfig{
+		Interval:         10 * time.Millisecond,
+		NodeCount:        3,
+		LeaveProbability: 1,
+	}, logger)
+
+	go func() { _ = m.Run(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		return len(net.NodeIDs()) == 0
+	}, 5*time.Second, 20*time.Millisecond, "mocker never let all nodes leave")
+}